@@ -65,7 +65,7 @@ func newReconciler(mgr manager.Manager) (*ReconcileDashboard, declarative.LabelM
 }
 
 func Add(mgr manager.Manager) error {
-	r, srcLabels := newReconciler(mgr)
+	r, _ := newReconciler(mgr)
 
 	c, err := controller.New("dashboard-controller", mgr, controller.Options{Reconciler: r})
 	if err != nil {
@@ -78,8 +78,10 @@ func Add(mgr manager.Manager) error {
 		return err
 	}
 
-	// Watch for changes to deployed objects
-	_, err = declarative.WatchAll(mgr.GetConfig(), c, r, srcLabels)
+	// Watch for changes to deployed objects. Reconciler.WatchAll, rather than declarative.WatchAll
+	// directly, ensures WithMetadataOnlyWatches (if configured in newReconciler) actually takes
+	// effect.
+	_, err = r.Reconciler.WatchAll(c)
 	if err != nil {
 		return err
 	}
@@ -4,8 +4,6 @@ import (
 	"context"
 	"fmt"
 
-	"sigs.k8s.io/controller-runtime/pkg/runtime/log"
-
 	addonsv1alpha1 "sigs.k8s.io/kubebuilder-declarative-pattern/pkg/patterns/addon/pkg/apis/v1alpha1"
 	"sigs.k8s.io/kubebuilder-declarative-pattern/pkg/patterns/declarative"
 	"sigs.k8s.io/kubebuilder-declarative-pattern/pkg/patterns/declarative/pkg/manifest"
@@ -27,26 +25,13 @@ const (
 
 // TransformApplicationFromStatus modifies the Application in the deployment based off the Addons status
 func TransformApplicationFromStatus(ctx context.Context, instance declarative.DeclarativeObject, objects *manifest.Objects) error {
-	log := log.Log
-
 	addonObject, ok := instance.(addonsv1alpha1.CommonObject)
 	if !ok {
 		return fmt.Errorf("instance %T was not an addonsv1alpha1.CommonObject", instance)
 	}
 
-	var app *manifest.Object
-	for _, o := range objects.Items {
-		if o.Group == "app.k8s.io" && o.Kind == "Application" {
-			if app != nil {
-				log.Info("cannot update application with multiple app.k8s.io/Application in manifest")
-				return nil
-			}
-			app = o
-		}
-	}
-
+	app := declarative.FindApplication(objects, declarative.DefaultApplicationGVKs, nil)
 	if app == nil {
-		log.Info("cannot transformApplication without an app.k8s.io/Application in the manifest")
 		return nil
 	}
 
@@ -8,36 +8,143 @@ import (
 	"sort"
 
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
 
 	"sigs.k8s.io/controller-runtime/pkg/runtime/log"
 	"sigs.k8s.io/kubebuilder-declarative-pattern/pkg/patterns/declarative/pkg/manifest"
 )
 
-func transformApplication(ctx context.Context, instance DeclarativeObject, objects *manifest.Objects, labelMaker LabelMaker) error {
-	log := log.Log
+// DefaultApplicationGVKs are the app.k8s.io Application GroupVersionKinds an ApplicationTransformer
+// recognizes unless overridden with WithSupportedApplicationGVKs.
+var DefaultApplicationGVKs = []schema.GroupVersionKind{
+	{Group: "app.k8s.io", Version: "v1beta1", Kind: "Application"},
+}
 
-	var app *manifest.Object
-	for _, o := range objects.Items {
-		if o.Group == "app.k8s.io" && o.Kind == "Application" {
-			if app != nil {
-				log.Info("cannot update application with multiple app.k8s.io/Application in manifest")
-				return nil
-			}
-			app = o
-		}
+// ApplicationSelector picks the Application object in a manifest to manage, for manifests that
+// contain more than one. Returning false for every candidate is equivalent to there being none.
+type ApplicationSelector = func(*manifest.Object) bool
+
+// ApplicationDescriptorMutator populates additional fields (type, owners, keywords, maintainers,
+// links, notes, ...) of a selected Application's spec.descriptor from the DeclarativeObject being
+// reconciled.
+type ApplicationDescriptorMutator = func(ctx context.Context, instance DeclarativeObject, app *manifest.Object) error
+
+// ApplicationTransformer is an ObjectTransform that keeps a manifest's app.k8s.io Application
+// object in sync with the rest of the deployment: its selector, componentGroupKinds, and any
+// descriptor fields populated by its mutators.
+type ApplicationTransformer struct {
+	gvks       []schema.GroupVersionKind
+	selector   ApplicationSelector
+	mutators   []ApplicationDescriptorMutator
+	labelMaker LabelMaker
+}
+
+// ApplicationOption configures an ApplicationTransformer built by NewApplicationTransformer or
+// WithApplication.
+type ApplicationOption func(*ApplicationTransformer)
+
+// WithManagedApplicationSelector selects which app.k8s.io Application object to manage when a
+// manifest contains more than one. Without a selector, a manifest's sole Application is managed,
+// and none is managed if there is more than one (preserving the pre-existing behavior).
+func WithManagedApplicationSelector(selector ApplicationSelector) ApplicationOption {
+	return func(t *ApplicationTransformer) {
+		t.selector = selector
+	}
+}
+
+// WithApplicationDescriptorMutator registers additional ApplicationDescriptorMutators to run
+// against the managed Application, beyond the version and assemblyPhase populated elsewhere (see
+// addon.TransformApplicationFromStatus).
+func WithApplicationDescriptorMutator(mutators ...ApplicationDescriptorMutator) ApplicationOption {
+	return func(t *ApplicationTransformer) {
+		t.mutators = append(t.mutators, mutators...)
+	}
+}
+
+// WithSupportedApplicationGVKs overrides the set of app.k8s.io Application GroupVersionKinds an
+// ApplicationTransformer recognizes in a manifest. Defaults to DefaultApplicationGVKs.
+func WithSupportedApplicationGVKs(gvks ...schema.GroupVersionKind) ApplicationOption {
+	return func(t *ApplicationTransformer) {
+		t.gvks = gvks
 	}
+}
 
+// NewApplicationTransformer builds an ApplicationTransformer for labelMaker, applying opts.
+func NewApplicationTransformer(labelMaker LabelMaker, opts ...ApplicationOption) *ApplicationTransformer {
+	t := &ApplicationTransformer{
+		gvks:       DefaultApplicationGVKs,
+		labelMaker: labelMaker,
+	}
+	for _, opt := range opts {
+		opt(t)
+	}
+	return t
+}
+
+// Transform locates the Application managed by t in objects and updates its selector,
+// componentGroupKinds, and descriptor fields. It is an ObjectTransform.
+func (t *ApplicationTransformer) Transform(ctx context.Context, instance DeclarativeObject, objects *manifest.Objects) error {
+	app := FindApplication(objects, t.gvks, t.selector)
 	if app == nil {
-		log.Info("cannot transformApplication without an app.k8s.io/Application in the manifest")
+		log.Log.Info("cannot transformApplication without a managed app.k8s.io Application in the manifest")
 		return nil
 	}
 
-	app.SetNestedFieldNoCopy(metav1.LabelSelector{MatchLabels: labelMaker(ctx, instance)}, "spec", "selector")
+	app.SetNestedFieldNoCopy(metav1.LabelSelector{MatchLabels: t.labelMaker(ctx, instance)}, "spec", "selector")
 	app.SetNestedFieldNoCopy(uniqueGroupVersionKind(objects), "spec", "componentGroupKinds")
 
+	for _, mutate := range t.mutators {
+		if err := mutate(ctx, instance, app); err != nil {
+			return err
+		}
+	}
+
 	return nil
 }
 
+// FindApplication returns the Application object in objects whose GroupVersionKind is one of
+// gvks. If selector is non-nil, it is used to pick the Application out of a manifest that
+// contains more than one; otherwise a manifest's sole Application is returned, and none is
+// returned (with a log line) if there is more than one.
+func FindApplication(objects *manifest.Objects, gvks []schema.GroupVersionKind, selector ApplicationSelector) *manifest.Object {
+	log := log.Log
+
+	var candidates []*manifest.Object
+	for _, o := range objects.Items {
+		if isApplicationGVK(o.GroupVersionKind(), gvks) {
+			candidates = append(candidates, o)
+		}
+	}
+
+	if selector != nil {
+		for _, o := range candidates {
+			if selector(o) {
+				return o
+			}
+		}
+		return nil
+	}
+
+	switch len(candidates) {
+	case 0:
+		return nil
+	case 1:
+		return candidates[0]
+	default:
+		log.Info("cannot update application with multiple app.k8s.io Applications in manifest; use WithManagedApplicationSelector to pick one")
+		return nil
+	}
+}
+
+func isApplicationGVK(gvk schema.GroupVersionKind, gvks []schema.GroupVersionKind) bool {
+	for _, candidate := range gvks {
+		if gvk == candidate {
+			return true
+		}
+	}
+	return false
+}
+
 // uniqueGroupKind returns all unique GroupKind defined in objects
 func uniqueGroupKind(objects *manifest.Objects) []metav1.GroupKind {
 	kinds := map[metav1.GroupKind]struct{}{}
@@ -0,0 +1,86 @@
+package declarative
+
+import (
+	"testing"
+
+	"sigs.k8s.io/kubebuilder-declarative-pattern/pkg/patterns/declarative/pkg/manifest"
+)
+
+func TestFindApplicationSingleCandidate(t *testing.T) {
+	objs := mustParseWaveObjects(t, `
+apiVersion: app.k8s.io/v1beta1
+kind: Application
+metadata:
+  name: my-app
+---
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: my-deployment
+`)
+
+	app := FindApplication(objs, DefaultApplicationGVKs, nil)
+	if app == nil {
+		t.Fatal("expected the sole Application to be returned, got nil")
+	}
+	if app.UnstructuredObject().GetName() != "my-app" {
+		t.Errorf("got Application %q, want my-app", app.UnstructuredObject().GetName())
+	}
+}
+
+func TestFindApplicationNoSelectorWithMultipleCandidatesReturnsNil(t *testing.T) {
+	objs := mustParseWaveObjects(t, `
+apiVersion: app.k8s.io/v1beta1
+kind: Application
+metadata:
+  name: my-app-1
+---
+apiVersion: app.k8s.io/v1beta1
+kind: Application
+metadata:
+  name: my-app-2
+`)
+
+	if app := FindApplication(objs, DefaultApplicationGVKs, nil); app != nil {
+		t.Errorf("expected nil with multiple Applications and no selector, got %q", app.UnstructuredObject().GetName())
+	}
+}
+
+func TestFindApplicationSelectorPicksAmongMultiple(t *testing.T) {
+	objs := mustParseWaveObjects(t, `
+apiVersion: app.k8s.io/v1beta1
+kind: Application
+metadata:
+  name: my-app-1
+---
+apiVersion: app.k8s.io/v1beta1
+kind: Application
+metadata:
+  name: my-app-2
+`)
+
+	selector := func(o *manifest.Object) bool {
+		return o.UnstructuredObject().GetName() == "my-app-2"
+	}
+
+	app := FindApplication(objs, DefaultApplicationGVKs, selector)
+	if app == nil {
+		t.Fatal("expected the selector to pick an Application, got nil")
+	}
+	if app.UnstructuredObject().GetName() != "my-app-2" {
+		t.Errorf("got Application %q, want my-app-2", app.UnstructuredObject().GetName())
+	}
+}
+
+func TestFindApplicationNoCandidates(t *testing.T) {
+	objs := mustParseWaveObjects(t, `
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: my-deployment
+`)
+
+	if app := FindApplication(objs, DefaultApplicationGVKs, nil); app != nil {
+		t.Errorf("expected nil with no Application in the manifest, got %q", app.UnstructuredObject().GetName())
+	}
+}
@@ -0,0 +1,38 @@
+package declarative
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os/exec"
+
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// DirectApplier is the default Applier: it shells out to `kubectl apply`, passing the manifest on
+// stdin. It is bypassed entirely when WithApplyStrategy(OrderedApply) or WithServerSideApply is
+// configured, both of which apply objects natively through the controller-runtime client instead.
+type DirectApplier struct{}
+
+var _ Applier = &DirectApplier{}
+
+// Apply shells out to `kubectl apply -f -`, passing manifestStr on stdin.
+func (a *DirectApplier) Apply(ctx context.Context, c client.Client, namespace string, manifestStr string, validate bool, extraArgs ...string) error {
+	args := []string{"apply", "-f", "-"}
+	if namespace != "" {
+		args = append(args, "-n", namespace)
+	}
+	args = append(args, fmt.Sprintf("--validate=%v", validate))
+	args = append(args, extraArgs...)
+
+	cmd := exec.CommandContext(ctx, "kubectl", args...)
+	cmd.Stdin = bytes.NewBufferString(manifestStr)
+
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+
+	if err := cmd.Run(); err != nil {
+		return fmt.Errorf("running kubectl apply: %v: %s", err, stderr.String())
+	}
+	return nil
+}
@@ -20,6 +20,7 @@ import (
 	"context"
 	"fmt"
 	"strings"
+	"time"
 
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/runtime"
@@ -42,8 +43,18 @@ type reconcilerParams struct {
 	objectTransformations []ObjectTransform
 	manifestController    ManifestController
 
-	prune             bool
-	preserveNamespace bool
+	prune               bool
+	preserveNamespace   bool
+	metadataOnlyWatches bool
+	applyStrategy       ApplyStrategy
+	readinessChecker    ReadinessChecker
+	waveTimeout         time.Duration
+
+	serverSideApply bool
+	fieldManager    string
+	forceOwnership  bool
+
+	applier Applier
 
 	sink       Sink
 	ownerFn    OwnerSelector
@@ -106,6 +117,16 @@ func WithManifestController(mc ManifestController) reconcilerOption {
 	}
 }
 
+// WithApplier overrides the Applier used when neither WithApplyStrategy(OrderedApply) nor
+// WithServerSideApply is configured. Defaults to &DirectApplier{}, which shells out to
+// `kubectl apply`.
+func WithApplier(applier Applier) reconcilerOption {
+	return func(p reconcilerParams) reconcilerParams {
+		p.applier = applier
+		return p
+	}
+}
+
 func withImageRegistryTransform(privateRegistry, imagePullSecret string) func(context.Context, DeclarativeObject, *manifest.Objects) error {
 	return func(c context.Context, o DeclarativeObject, m *manifest.Objects) error {
 		return applyImageRegistry(c, o, m, privateRegistry, imagePullSecret)
@@ -195,6 +216,73 @@ func WithStatus(status Status) reconcilerOption {
 	}
 }
 
+// WithMetadataOnlyWatches establishes the reconciler's watches on deployed objects using
+// PartialObjectMetadata requests instead of fetching full objects. This considerably reduces
+// memory and network cost on clusters where an addon manages high-churn, high-cardinality kinds
+// (Pods, Endpoints, ConfigMaps, etc.), since only the object's identity - not its spec/status - is
+// needed to know that a reconcile should be triggered.
+//
+// A Reconciler with this option set passes WithMetadataOnlyWatch() through when
+// Reconciler.WatchAll is called to establish its watches; a caller that calls the package-level
+// WatchAll directly instead must consult Reconciler.MetadataOnlyWatches() itself.
+func WithMetadataOnlyWatches() reconcilerOption {
+	return func(p reconcilerParams) reconcilerParams {
+		p.metadataOnlyWatches = true
+		return p
+	}
+}
+
+// WithApplyStrategy selects how the resolved manifest is applied to the cluster. The default
+// strategy applies the whole manifest in one shot; see OrderedApply for an alternative that
+// applies the manifest in dependency-ordered waves, waiting for readiness between them.
+func WithApplyStrategy(strategy ApplyStrategy) reconcilerOption {
+	return func(p reconcilerParams) reconcilerParams {
+		p.applyStrategy = strategy
+		return p
+	}
+}
+
+// WithReadinessChecker overrides the ReadinessChecker used between waves under OrderedApply.
+// Ignored unless WithApplyStrategy(OrderedApply) is also set.
+func WithReadinessChecker(checker ReadinessChecker) reconcilerOption {
+	return func(p reconcilerParams) reconcilerParams {
+		p.readinessChecker = checker
+		return p
+	}
+}
+
+// WithWaveTimeout overrides WaveTimeout, the time OrderedApply waits for a wave's objects to
+// become ready before failing the reconcile with a WaveN condition.
+func WithWaveTimeout(timeout time.Duration) reconcilerOption {
+	return func(p reconcilerParams) reconcilerParams {
+		p.waveTimeout = timeout
+		return p
+	}
+}
+
+// WithServerSideApply switches the reconciler from shelling out to `kubectl apply` to applying
+// manifest.Objects natively via client.Patch(ctx, obj, client.Apply, ...) on the controller-runtime
+// client, using fieldManager as the field owner. This drops the kubectl binary dependency and
+// gives per-field ownership semantics, letting multiple controllers cooperatively own the same
+// object.
+//
+// When force is false, a Conflict response from the apiserver is translated into a FieldConflict
+// condition (see FieldConflictConditionType) rather than failing the reconcile outright, so
+// operators can see which fields conflicted with which field managers without dropping into
+// cluster logs. When force is true, conflicts are resolved by taking ownership
+// (client.ForceOwnership).
+//
+// WithApplyPrune's behavior is re-implemented under this mode by listing objects labeled with the
+// addon's LabelMaker output and deleting those that are not in the current manifest set.
+func WithServerSideApply(fieldManager string, force bool) reconcilerOption {
+	return func(p reconcilerParams) reconcilerParams {
+		p.serverSideApply = true
+		p.fieldManager = fieldManager
+		p.forceOwnership = force
+		return p
+	}
+}
+
 // WithPreserveNamespace preserves the namespaces defined in the deployment manifest
 // instead of matching the namespace of the DeclarativeObject
 func WithPreserveNamespace() reconcilerOption {
@@ -206,11 +294,20 @@ func WithPreserveNamespace() reconcilerOption {
 
 // WithManagedApplication is a transform that will modify the Application object
 // in the deployment to match the configuration of the rest of the deployment.
+//
+// Deprecated: use WithApplication, which composes the same LabelMaker with an
+// ApplicationSelector (for manifests containing more than one app.k8s.io Application) and
+// ApplicationDescriptorMutators (to populate descriptor fields beyond version and assemblyPhase).
 func WithManagedApplication(labelMaker LabelMaker) reconcilerOption {
+	return WithApplication(labelMaker)
+}
+
+// WithApplication is a transform that will modify the app.k8s.io Application object in the
+// deployment to match the configuration of the rest of the deployment, and of opts.
+func WithApplication(labelMaker LabelMaker, opts ...ApplicationOption) reconcilerOption {
+	t := NewApplicationTransformer(labelMaker, opts...)
 	return func(p reconcilerParams) reconcilerParams {
-		p.objectTransformations = append(p.objectTransformations, func(ctx context.Context, instance DeclarativeObject, objects *manifest.Objects) error {
-			return transformApplication(ctx, instance, objects, labelMaker)
-		})
+		p.objectTransformations = append(p.objectTransformations, t.Transform)
 		return p
 	}
 }
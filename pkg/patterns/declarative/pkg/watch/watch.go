@@ -0,0 +1,141 @@
+// Package watch establishes watches against arbitrary GroupVersionKinds discovered at runtime,
+// funnelling change notifications into a single channel that a controller can consume via
+// controller-runtime's source.Channel.
+package watch
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	apiwatch "k8s.io/apimachinery/pkg/watch"
+	"k8s.io/client-go/discovery"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/metadata"
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/restmapper"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/runtime/log"
+)
+
+// dynamicWatch implements declarative.DynamicWatch, establishing a watch per GroupVersionKind the
+// first time it is requested and forwarding every event on it, keyed by the registered target, to
+// a single output channel.
+type dynamicWatch struct {
+	config rest.Config
+
+	dynamicClient  dynamic.Interface
+	metadataClient metadata.Interface
+	mapper         *restmapper.DeferredDiscoveryRESTMapper
+
+	mutex   sync.Mutex
+	started map[schema.GroupVersionKind]bool
+
+	events chan event.GenericEvent
+}
+
+// NewDynamicWatch creates a DynamicWatch, along with the channel that its watches deliver events
+// on. The channel should be wired into a controller via source.Channel.
+func NewDynamicWatch(config rest.Config) (*dynamicWatch, chan event.GenericEvent, error) {
+	dynamicClient, err := dynamic.NewForConfig(&config)
+	if err != nil {
+		return nil, nil, fmt.Errorf("building dynamic client: %v", err)
+	}
+	metadataClient, err := metadata.NewForConfig(&config)
+	if err != nil {
+		return nil, nil, fmt.Errorf("building metadata client: %v", err)
+	}
+	discoveryClient, err := discovery.NewDiscoveryClientForConfig(&config)
+	if err != nil {
+		return nil, nil, fmt.Errorf("building discovery client: %v", err)
+	}
+
+	events := make(chan event.GenericEvent, 100)
+	dw := &dynamicWatch{
+		config:         config,
+		dynamicClient:  dynamicClient,
+		metadataClient: metadataClient,
+		mapper:         restmapper.NewDeferredDiscoveryRESTMapper(discoveryClient),
+		started:        make(map[schema.GroupVersionKind]bool),
+		events:         events,
+	}
+	return dw, events, nil
+}
+
+// Add registers a watch for changes to 'trigger' filtered by 'options', raising an event targeting
+// 'target' whenever a matching object changes. Watches are only ever established once per
+// GroupVersionKind; subsequent calls for an already-watched GVK are a no-op.
+//
+// When metadataOnly is true, the watch is established against PartialObjectMetadata rather than
+// the full object: the request's Accept header is set to
+// "application/json;as=PartialObjectMetadataList;g=meta.k8s.io;v=v1" and responses are decoded
+// into metav1.PartialObjectMetadata, so the apiserver and client never marshal the object's
+// spec/status. This is a pure cost optimization - the event raised on 'target' is identical either
+// way, since all the reconciler needs from a watch is "something changed", not "what changed".
+func (d *dynamicWatch) Add(trigger schema.GroupVersionKind, options metav1.ListOptions, target metav1.ObjectMeta, metadataOnly bool) error {
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+
+	if d.started[trigger] {
+		return nil
+	}
+
+	mapping, err := d.mapper.RESTMapping(trigger.GroupKind(), trigger.Version)
+	if err != nil {
+		return fmt.Errorf("mapping %v to a resource: %v", trigger, err)
+	}
+
+	var startWatch func(ctx context.Context) (apiwatch.Interface, error)
+	if metadataOnly {
+		startWatch = func(ctx context.Context) (apiwatch.Interface, error) {
+			return d.metadataClient.Resource(mapping.Resource).Watch(ctx, options)
+		}
+	} else {
+		startWatch = func(ctx context.Context) (apiwatch.Interface, error) {
+			return d.dynamicClient.Resource(mapping.Resource).Watch(ctx, options)
+		}
+	}
+
+	ctx := context.Background()
+	w, err := startWatch(ctx)
+	if err != nil {
+		return fmt.Errorf("starting watch on %v: %v", trigger, err)
+	}
+
+	go d.forward(ctx, trigger, target, w)
+
+	d.started[trigger] = true
+	return nil
+}
+
+// forward relays every event off of w as a GenericEvent keyed by target, so that the controller
+// enqueues a reconcile for the DeclarativeObject that owns the watched GVK - not for the watched
+// object itself.
+func (d *dynamicWatch) forward(ctx context.Context, trigger schema.GroupVersionKind, target metav1.ObjectMeta, w apiwatch.Interface) {
+	log := log.Log
+	defer w.Stop()
+
+	for range w.ResultChan() {
+		d.events <- event.GenericEvent{Object: &targetObject{ObjectMeta: target}}
+	}
+
+	log.WithValues("GroupVersionKind", trigger.String()).Info("watch closed")
+}
+
+// targetObject adapts an ObjectMeta into the minimal runtime.Object the event/handler machinery
+// needs in order to enqueue a reconcile.Request for it; the watched object's identity is
+// deliberately discarded, only the registered target survives.
+type targetObject struct {
+	metav1.ObjectMeta
+}
+
+func (o *targetObject) GetObjectKind() schema.ObjectKind { return schema.EmptyObjectKind }
+
+func (o *targetObject) DeepCopyObject() runtime.Object {
+	out := *o
+	out.ObjectMeta = *o.ObjectMeta.DeepCopy()
+	return &out
+}
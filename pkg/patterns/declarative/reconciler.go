@@ -0,0 +1,293 @@
+/*
+Copyright 2019 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package declarative
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/rest"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	"sigs.k8s.io/controller-runtime/pkg/runtime/log"
+
+	"sigs.k8s.io/kubebuilder-declarative-pattern/pkg/patterns/declarative/pkg/manifest"
+)
+
+// DeclarativeObject is the custom resource a Reconciler manages: a runtime.Object with the usual
+// ObjectMeta accessors.
+type DeclarativeObject interface {
+	runtime.Object
+	metav1.Object
+}
+
+// Status lets a Reconciler report back onto a DeclarativeObject: whether the most recent
+// reconcile succeeded, and point-in-time conditions (ManifestUnverifiedConditionType,
+// WaveConditionType(n), FieldConflictConditionType, ...) raised along the way.
+type Status interface {
+	// Reconciled records the outcome - success or failure - of fully reconciling instance.
+	Reconciled(ctx context.Context, instance DeclarativeObject, manifestStr string, err error) (bool, error)
+	// SetCondition sets a single named condition on instance's status, without otherwise
+	// altering it, to surface a problem that interrupted reconciliation (or clear one that no
+	// longer applies, by passing an empty reason).
+	SetCondition(ctx context.Context, instance DeclarativeObject, conditionType string, reason string) error
+}
+
+// Applier applies a resolved manifest to the cluster. DirectApplier is the default,
+// shelling out to `kubectl apply`; it is bypassed when WithApplyStrategy(OrderedApply) or
+// WithServerSideApply is configured.
+type Applier interface {
+	Apply(ctx context.Context, c client.Client, namespace string, manifestStr string, validate bool, extraArgs ...string) error
+}
+
+// Reconciler reconciles a DeclarativeObject by resolving it to a manifest, transforming it, and
+// applying it to the cluster, using whichever apply strategy its reconcilerOptions configure.
+type Reconciler struct {
+	prototype DeclarativeObject
+	client    client.Client
+	config    *rest.Config
+
+	options reconcilerParams
+
+	// managedGVKsMu guards managedGVKs.
+	managedGVKsMu sync.Mutex
+	// managedGVKs records, per managed instance, every GroupVersionKind any manifest version
+	// applied under WithServerSideApply has ever contained. It is in-memory only - reset on
+	// restart - so pruneServerSide does not prune a kind dropped from the manifest before a
+	// restart, since that kind is never recorded again; it is populated on every reconcile specifically so that kind is not
+	// missed across repeated reconciles.
+	managedGVKs map[types.NamespacedName][]schema.GroupVersionKind
+}
+
+// Init configures the Reconciler to manage prototype, applying opts. name is not used.
+func (r *Reconciler) Init(mgr manager.Manager, prototype DeclarativeObject, name string, opts ...reconcilerOption) error {
+	r.prototype = prototype
+	r.client = mgr.GetClient()
+	r.config = mgr.GetConfig()
+
+	params := reconcilerParams{}
+	for _, opt := range opts {
+		params = opt(params)
+	}
+	if params.manifestController == nil {
+		params.manifestController = DefaultManifestLoader()
+	}
+	r.options = params
+
+	return nil
+}
+
+// MetadataOnlyWatches reports whether WithMetadataOnlyWatches was configured, so that a caller's
+// Add() func can pass WithMetadataOnlyWatch() to its own call to WatchAll if it calls that
+// directly instead of going through the Reconciler.WatchAll convenience method below.
+func (r *Reconciler) MetadataOnlyWatches() bool {
+	return r.options.metadataOnlyWatches
+}
+
+// WatchAll establishes the reconciler's dynamic watches on ctrl, honoring
+// WithMetadataOnlyWatches.
+func (r *Reconciler) WatchAll(ctrl controller.Controller) (chan struct{}, error) {
+	var opts []WatchOption
+	if r.options.metadataOnlyWatches {
+		opts = append(opts, WithMetadataOnlyWatch())
+	}
+	return WatchAll(r.config, ctrl, r, opts...)
+}
+
+var _ Source = &Reconciler{}
+
+// SetSink implements Source, recording the Sink that WatchAll should notify of reconciled
+// objects.
+func (r *Reconciler) SetSink(sink Sink) {
+	r.options.sink = sink
+}
+
+// Reconcile fetches the DeclarativeObject named by req and brings the cluster in line with its
+// manifest.
+func (r *Reconciler) Reconcile(ctx context.Context, req reconcile.Request) (reconcile.Result, error) {
+	instance := r.prototype.DeepCopyObject().(DeclarativeObject)
+	if err := r.client.Get(ctx, req.NamespacedName, instance); err != nil {
+		if apierrors.IsNotFound(err) {
+			return reconcile.Result{}, nil
+		}
+		return reconcile.Result{}, err
+	}
+
+	err := r.reconcileExists(ctx, instance)
+	if r.options.status != nil {
+		if _, statusErr := r.options.status.Reconciled(ctx, instance, "", err); statusErr != nil {
+			log.Log.Error(statusErr, "updating status")
+		}
+	}
+	return reconcile.Result{}, err
+}
+
+func (r *Reconciler) reconcileExists(ctx context.Context, instance DeclarativeObject) error {
+	manifestStr, err := r.options.manifestController.ResolveManifest(ctx, instance)
+	if err != nil {
+		return fmt.Errorf("resolving manifest: %v", err)
+	}
+
+	for _, op := range r.options.rawManifestOperations {
+		manifestStr, err = op(ctx, instance, manifestStr)
+		if err != nil {
+			var verifyErr *VerificationError
+			if r.options.status != nil && errors.As(err, &verifyErr) {
+				r.options.status.SetCondition(ctx, instance, ManifestUnverifiedConditionType, err.Error())
+			}
+			return fmt.Errorf("applying raw manifest operation: %v", err)
+		}
+	}
+
+	objects, err := manifest.ParseObjects(ctx, manifestStr)
+	if err != nil {
+		return fmt.Errorf("parsing manifest: %v", err)
+	}
+
+	for _, transform := range r.options.objectTransformations {
+		if err := transform(ctx, instance, objects); err != nil {
+			return fmt.Errorf("applying object transformation: %v", err)
+		}
+	}
+
+	if r.options.sink != nil {
+		if err := r.options.sink.Notify(ctx, instance, objects); err != nil {
+			return fmt.Errorf("notifying sink: %v", err)
+		}
+	}
+
+	return r.apply(ctx, instance, manifestStr, objects)
+}
+
+// apply applies objects to the cluster using the configured strategy: WithApplyStrategy(OrderedApply)
+// computes dependency-ordered waves and applies them one at a time via ApplyOrdered, waiting for
+// each wave's readiness before starting the next; WithServerSideApply patches objects natively via
+// server-side apply and, if WithApplyPrune is also set, prunes anything no longer in the manifest;
+// otherwise objects are applied in one shot via r.options.applier (DirectApplier unless overridden
+// with WithApplier).
+func (r *Reconciler) apply(ctx context.Context, instance DeclarativeObject, manifestStr string, objects *manifest.Objects) error {
+	r.recordManagedGVKs(instance, objects)
+
+	if r.options.applyStrategy == OrderedApply {
+		waves, err := ComputeWaves(objects)
+		if err != nil {
+			return fmt.Errorf("computing apply waves: %v", err)
+		}
+		if err := ApplyOrdered(ctx, r.client, waves, r.options.readinessChecker, r.options.waveTimeout); err != nil {
+			var waveErr *WaveError
+			if r.options.status != nil && errors.As(err, &waveErr) {
+				r.options.status.SetCondition(ctx, instance, WaveConditionType(waveErr.Wave), waveErr.Error())
+			}
+			return err
+		}
+		return nil
+	}
+
+	if r.options.serverSideApply {
+		conflicts, err := applyServerSide(ctx, r.client, objects, r.options.fieldManager, r.options.forceOwnership)
+		if err != nil {
+			return err
+		}
+		if len(conflicts) > 0 && r.options.status != nil {
+			r.options.status.SetCondition(ctx, instance, FieldConflictConditionType, formatFieldConflicts(conflicts))
+		}
+		if r.options.prune {
+			return r.pruneServerSide(ctx, instance, objects)
+		}
+		return nil
+	}
+
+	applier := r.options.applier
+	if applier == nil {
+		applier = &DirectApplier{}
+	}
+
+	var extraArgs []string
+	if r.options.prune && r.options.labelMaker != nil {
+		extraArgs = append(extraArgs, "--prune")
+	}
+	namespace := instance.GetNamespace()
+	if r.options.preserveNamespace {
+		namespace = ""
+	}
+	return applier.Apply(ctx, r.client, namespace, manifestStr, true, extraArgs...)
+}
+
+// pruneServerSide deletes objects previously applied for instance under WithServerSideApply that
+// are no longer in objects, scoped to every GroupVersionKind recorded for instance in
+// r.managedGVKs - not just the kinds in the current manifest - so a kind dropped entirely from the
+// manifest is still pruned.
+func (r *Reconciler) pruneServerSide(ctx context.Context, instance DeclarativeObject, objects *manifest.Objects) error {
+	key := types.NamespacedName{Namespace: instance.GetNamespace(), Name: instance.GetName()}
+
+	r.managedGVKsMu.Lock()
+	previouslyManagedGVKs := r.managedGVKs[key]
+	r.managedGVKsMu.Unlock()
+
+	return pruneServerSide(ctx, r.client, instance, objects, r.options.labelMaker, previouslyManagedGVKs)
+}
+
+// recordManagedGVKs merges the GroupVersionKinds in objects into r.managedGVKs for instance, so
+// that a later pruneServerSide call still knows about a kind even after it is removed from the
+// manifest.
+func (r *Reconciler) recordManagedGVKs(instance DeclarativeObject, objects *manifest.Objects) {
+	if !r.options.serverSideApply || !r.options.prune {
+		return
+	}
+
+	key := types.NamespacedName{Namespace: instance.GetNamespace(), Name: instance.GetName()}
+	seen := map[schema.GroupVersionKind]bool{}
+
+	r.managedGVKsMu.Lock()
+	defer r.managedGVKsMu.Unlock()
+	if r.managedGVKs == nil {
+		r.managedGVKs = map[types.NamespacedName][]schema.GroupVersionKind{}
+	}
+	for _, gvk := range r.managedGVKs[key] {
+		seen[gvk] = true
+	}
+	for _, gvk := range uniqueGroupVersionKind(objects) {
+		seen[gvk] = true
+	}
+
+	var merged []schema.GroupVersionKind
+	for gvk := range seen {
+		merged = append(merged, gvk)
+	}
+	r.managedGVKs[key] = merged
+}
+
+// formatFieldConflicts renders conflicts for a FieldConflictConditionType reason message.
+func formatFieldConflicts(conflicts []FieldConflict) string {
+	msg := ""
+	for i, c := range conflicts {
+		if i > 0 {
+			msg += "; "
+		}
+		msg += fmt.Sprintf("%s: field %q owned by %s", c.Object, c.Field, c.Manager)
+	}
+	return msg
+}
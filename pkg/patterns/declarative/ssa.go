@@ -0,0 +1,130 @@
+package declarative
+
+import (
+	"context"
+	"fmt"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"sigs.k8s.io/kubebuilder-declarative-pattern/pkg/patterns/declarative/pkg/manifest"
+)
+
+// FieldConflictConditionType is the condition surfaced on a DeclarativeObject's Status when
+// WithServerSideApply is configured with force=false and the apiserver rejects an apply because
+// another field manager owns a conflicting field.
+const FieldConflictConditionType = "FieldConflict"
+
+// FieldConflict describes a single field that another manager owns, preventing a non-forced
+// server-side apply from proceeding.
+type FieldConflict struct {
+	// Object identifies the manifest object the conflict occurred on, as "<group>/<kind>/<name>".
+	Object string
+	// Field is the JSON path of the conflicting field, as reported by the apiserver.
+	Field string
+	// Manager is the apiserver's message for the conflict cause, which names the field manager
+	// that currently owns Field; when the error carries no per-field causes, it is the whole
+	// error message.
+	Manager string
+}
+
+// applyServerSide applies every object in objs via server-side apply, using fieldManager as the
+// field owner. When force is false, a Conflict response from the apiserver for a given object is
+// recorded as one or more FieldConflicts rather than aborting the whole apply; all other errors
+// abort immediately.
+func applyServerSide(ctx context.Context, c client.Client, objs *manifest.Objects, fieldManager string, force bool) ([]FieldConflict, error) {
+	var conflicts []FieldConflict
+
+	opts := []client.PatchOption{client.FieldOwner(fieldManager)}
+	if force {
+		opts = append(opts, client.ForceOwnership)
+	}
+
+	for _, o := range objs.Items {
+		u := o.UnstructuredObject()
+		if err := c.Patch(ctx, u, client.Apply, opts...); err != nil {
+			if !force && apierrors.IsConflict(err) {
+				conflicts = append(conflicts, fieldConflictsFrom(o, err)...)
+				continue
+			}
+			return conflicts, fmt.Errorf("applying %s/%s %s: %v", o.Group, o.Kind, u.GetName(), err)
+		}
+	}
+
+	return conflicts, nil
+}
+
+// fieldConflictsFrom extracts the conflicting fields and their owning managers from a server-side
+// apply Conflict error returned by the apiserver.
+func fieldConflictsFrom(o *manifest.Object, err error) []FieldConflict {
+	object := dependencyKey(o)
+
+	statusErr, ok := err.(apierrors.APIStatus)
+	if !ok || statusErr.Status().Details == nil {
+		return []FieldConflict{{Object: object, Field: "", Manager: err.Error()}}
+	}
+
+	var conflicts []FieldConflict
+	for _, cause := range statusErr.Status().Details.Causes {
+		conflicts = append(conflicts, FieldConflict{
+			Object:  object,
+			Field:   string(cause.Field),
+			Manager: cause.Message,
+		})
+	}
+	if len(conflicts) == 0 {
+		conflicts = append(conflicts, FieldConflict{Object: object, Field: "", Manager: err.Error()})
+	}
+	return conflicts
+}
+
+// pruneServerSide deletes objects previously applied for dest but no longer present in objs. It
+// lists candidates using the labels labelMaker produces for dest, which requires every object
+// applied under WithServerSideApply to also carry those labels (as WithLabels already arranges).
+//
+// previouslyManagedGVKs must include every GroupVersionKind any earlier manifest version for dest
+// applied, not just those in the current manifest: a kind dropped entirely between versions (e.g.
+// a CronJob removed from the addon) has none of its instances in objs, so uniqueGroupVersionKind
+// alone would never list - and so never prune - them.
+func pruneServerSide(ctx context.Context, c client.Client, dest DeclarativeObject, objs *manifest.Objects, labelMaker LabelMaker, previouslyManagedGVKs []schema.GroupVersionKind) error {
+	if labelMaker == nil {
+		return fmt.Errorf("server-side apply pruning requires WithLabels to be configured")
+	}
+
+	want := map[string]bool{}
+	for _, o := range objs.Items {
+		want[dependencyKey(o)] = true
+	}
+
+	gvks := map[schema.GroupVersionKind]bool{}
+	for _, gvk := range uniqueGroupVersionKind(objs) {
+		gvks[gvk] = true
+	}
+	for _, gvk := range previouslyManagedGVKs {
+		gvks[gvk] = true
+	}
+
+	for gvk := range gvks {
+		list := &unstructured.UnstructuredList{}
+		list.SetGroupVersionKind(gvk.GroupVersion().WithKind(gvk.Kind + "List"))
+
+		if err := c.List(ctx, list, client.MatchingLabels(labelMaker(ctx, dest))); err != nil {
+			return fmt.Errorf("listing %v for pruning: %v", gvk, err)
+		}
+
+		for i := range list.Items {
+			item := &list.Items[i]
+			key := fmt.Sprintf("%s/%s/%s", gvk.Group, gvk.Kind, item.GetName())
+			if want[key] {
+				continue
+			}
+			if err := c.Delete(ctx, item); err != nil {
+				return fmt.Errorf("pruning %s: %v", key, err)
+			}
+		}
+	}
+
+	return nil
+}
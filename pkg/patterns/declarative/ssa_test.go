@@ -0,0 +1,88 @@
+package declarative
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+type fakeConflictStatusError struct {
+	status metav1.Status
+}
+
+func (e *fakeConflictStatusError) Error() string          { return e.status.Message }
+func (e *fakeConflictStatusError) Status() metav1.Status { return e.status }
+
+func TestFieldConflictsFromWithCauses(t *testing.T) {
+	objs := mustParseWaveObjects(t, `
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: my-deployment
+`)
+	o := objs.Items[0]
+
+	err := &fakeConflictStatusError{status: metav1.Status{
+		Message: "conflict",
+		Details: &metav1.StatusDetails{
+			Causes: []metav1.StatusCause{
+				{Field: "spec.replicas", Message: "kubectl-client-side-apply"},
+				{Field: "spec.template.spec.containers[0].image", Message: "helm"},
+			},
+		},
+	}}
+
+	conflicts := fieldConflictsFrom(o, err)
+	if len(conflicts) != 2 {
+		t.Fatalf("expected 2 conflicts, got %d: %+v", len(conflicts), conflicts)
+	}
+	if conflicts[0].Field != "spec.replicas" || conflicts[0].Manager != "kubectl-client-side-apply" {
+		t.Errorf("unexpected first conflict: %+v", conflicts[0])
+	}
+	if conflicts[1].Field != "spec.template.spec.containers[0].image" || conflicts[1].Manager != "helm" {
+		t.Errorf("unexpected second conflict: %+v", conflicts[1])
+	}
+	wantObject := fmt.Sprintf("%s/%s/%s", o.Group, o.Kind, o.UnstructuredObject().GetName())
+	for _, c := range conflicts {
+		if c.Object != wantObject {
+			t.Errorf("conflict.Object = %q, want %q", c.Object, wantObject)
+		}
+	}
+}
+
+func TestFieldConflictsFromWithoutStatusDetails(t *testing.T) {
+	objs := mustParseWaveObjects(t, `
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: my-deployment
+`)
+	o := objs.Items[0]
+
+	err := fmt.Errorf("some non-apierrors conflict")
+
+	conflicts := fieldConflictsFrom(o, err)
+	if len(conflicts) != 1 {
+		t.Fatalf("expected a single fallback conflict, got %d: %+v", len(conflicts), conflicts)
+	}
+	if conflicts[0].Field != "" || conflicts[0].Manager != err.Error() {
+		t.Errorf("unexpected fallback conflict: %+v", conflicts[0])
+	}
+}
+
+func TestPruneServerSideRequiresLabelMaker(t *testing.T) {
+	objs := mustParseWaveObjects(t, `
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: my-deployment
+`)
+
+	err := pruneServerSide(context.Background(), nil, nil, objs, nil, []schema.GroupVersionKind{})
+	if err == nil {
+		t.Fatal("expected an error when labelMaker is nil, got nil")
+	}
+}
@@ -0,0 +1,120 @@
+package declarative
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/hex"
+	"encoding/pem"
+	"fmt"
+	"io/ioutil"
+)
+
+// ManifestUnverifiedConditionType is the condition surfaced on a DeclarativeObject's Status when
+// a configured ManifestVerifier rejects the resolved manifest.
+const ManifestUnverifiedConditionType = "ManifestUnverified"
+
+// ManifestVerifier validates the raw manifest resolved for a DeclarativeObject before it is
+// applied, returning a non-nil error - a *VerificationError - if the manifest should not be
+// trusted.
+type ManifestVerifier interface {
+	Verify(ctx context.Context, instance DeclarativeObject, manifest string) error
+}
+
+// VerificationError is returned by a ManifestVerifier when a manifest fails validation. The
+// reconciler surfaces it as a ManifestUnverified condition via the Status interface and refuses
+// to apply the manifest, rather than falling back to applying it unverified.
+type VerificationError struct {
+	Reason string
+}
+
+func (e *VerificationError) Error() string {
+	return fmt.Sprintf("manifest failed verification: %s", e.Reason)
+}
+
+// WithVerifiedManifest adds a ManifestOperation that runs verifier against the raw manifest
+// resolved by the configured ManifestController (DefaultManifestLoader.ResolveManifest, unless
+// overridden by WithManifestController), rejecting the reconcile if it does not validate. This
+// lets operators safely consume manifests from untrusted mirrors or gitops caches.
+func WithVerifiedManifest(verifier ManifestVerifier) reconcilerOption {
+	return WithRawManifestOperation(func(ctx context.Context, instance DeclarativeObject, raw string) (string, error) {
+		if err := verifier.Verify(ctx, instance, raw); err != nil {
+			return "", err
+		}
+		return raw, nil
+	})
+}
+
+// DetachedSignatureVerifier verifies a manifest against a detached signature: a sibling file with
+// the same name as the manifest plus a ".sig" suffix, signed by one of a configured set of
+// ed25519 or ECDSA public keys. Verification succeeds if the signature validates against any one
+// of the configured keys.
+type DetachedSignatureVerifier struct {
+	// ManifestPath is the path to the manifest on disk; the signature is read from
+	// ManifestPath + ".sig".
+	ManifestPath string
+	// PublicKeys are PEM-encoded ed25519/ECDSA public keys trusted to sign manifests.
+	PublicKeys []string
+}
+
+func (v *DetachedSignatureVerifier) Verify(ctx context.Context, instance DeclarativeObject, manifest string) error {
+	sig, err := ioutil.ReadFile(v.ManifestPath + ".sig")
+	if err != nil {
+		return &VerificationError{Reason: fmt.Sprintf("reading detached signature %s.sig: %v", v.ManifestPath, err)}
+	}
+
+	digest := sha256.Sum256([]byte(manifest))
+
+	for _, keyPEM := range v.PublicKeys {
+		block, _ := pem.Decode([]byte(keyPEM))
+		if block == nil {
+			continue
+		}
+		pub, err := x509.ParsePKIXPublicKey(block.Bytes)
+		if err != nil {
+			continue
+		}
+		switch key := pub.(type) {
+		case ed25519.PublicKey:
+			if ed25519.Verify(key, []byte(manifest), sig) {
+				return nil
+			}
+		case *ecdsa.PublicKey:
+			if ecdsa.VerifyASN1(key, digest[:], sig) {
+				return nil
+			}
+		}
+	}
+
+	return &VerificationError{Reason: "signature did not validate against any configured public key"}
+}
+
+// DigestVerifier pins an expected sha256 manifest digest per channel/version, for reconcilers
+// whose DeclarativeObject spec names a channel and/or version to resolve a manifest from. Digests
+// are hex-encoded sha256 sums of the raw, resolved manifest.
+type DigestVerifier struct {
+	// Digests maps a channel/version key, as returned by Key, to its expected sha256 digest.
+	Digests map[string]string
+	// Key returns the channel/version key to look up in Digests for a given DeclarativeObject.
+	Key func(instance DeclarativeObject) string
+}
+
+func (v *DigestVerifier) Verify(ctx context.Context, instance DeclarativeObject, manifest string) error {
+	if v.Key == nil {
+		return &VerificationError{Reason: "Key is not configured"}
+	}
+
+	key := v.Key(instance)
+	want, ok := v.Digests[key]
+	if !ok {
+		return &VerificationError{Reason: fmt.Sprintf("no pinned digest configured for %q", key)}
+	}
+
+	sum := sha256.Sum256([]byte(manifest))
+	if got := hex.EncodeToString(sum[:]); got != want {
+		return &VerificationError{Reason: fmt.Sprintf("manifest digest %s for %q does not match pinned digest %s", got, key, want)}
+	}
+	return nil
+}
@@ -0,0 +1,60 @@
+package declarative
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"testing"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+func TestDigestVerifierRequiresKeyFunc(t *testing.T) {
+	v := &DigestVerifier{Digests: map[string]string{}}
+
+	err := v.Verify(context.Background(), &unstructured.Unstructured{}, "manifest contents")
+	if err == nil {
+		t.Fatal("expected an error when Key is not configured, got nil")
+	}
+	if _, ok := err.(*VerificationError); !ok {
+		t.Errorf("expected a *VerificationError, got %T: %v", err, err)
+	}
+}
+
+func TestDigestVerifierMatchingDigest(t *testing.T) {
+	manifest := "manifest contents"
+	sum := sha256.Sum256([]byte(manifest))
+
+	v := &DigestVerifier{
+		Digests: map[string]string{"stable": hex.EncodeToString(sum[:])},
+		Key:     func(instance DeclarativeObject) string { return "stable" },
+	}
+
+	if err := v.Verify(context.Background(), &unstructured.Unstructured{}, manifest); err != nil {
+		t.Errorf("expected a matching digest to verify, got error: %v", err)
+	}
+}
+
+func TestDigestVerifierMismatchedDigest(t *testing.T) {
+	v := &DigestVerifier{
+		Digests: map[string]string{"stable": "0000000000000000000000000000000000000000000000000000000000000000"},
+		Key:     func(instance DeclarativeObject) string { return "stable" },
+	}
+
+	err := v.Verify(context.Background(), &unstructured.Unstructured{}, "manifest contents")
+	if err == nil {
+		t.Fatal("expected an error for a mismatched digest, got nil")
+	}
+}
+
+func TestDigestVerifierUnknownKey(t *testing.T) {
+	v := &DigestVerifier{
+		Digests: map[string]string{"stable": "irrelevant"},
+		Key:     func(instance DeclarativeObject) string { return "unknown-channel" },
+	}
+
+	err := v.Verify(context.Background(), &unstructured.Unstructured{}, "manifest contents")
+	if err == nil {
+		t.Fatal("expected an error for a key with no pinned digest, got nil")
+	}
+}
@@ -22,12 +22,40 @@ type Source interface {
 }
 
 type DynamicWatch interface {
-	// Add registers a watch for changes to 'trigger' filtered by 'options' to raise an event on 'target'
-	Add(trigger schema.GroupVersionKind, options metav1.ListOptions, target metav1.ObjectMeta) error
+	// Add registers a watch for changes to 'trigger' filtered by 'options' to raise an event on 'target'.
+	// When metadataOnly is true, the watch is established against PartialObjectMetadata rather than the
+	// full object, which is considerably cheaper for high-churn, high-cardinality kinds (Pods, Endpoints,
+	// ConfigMaps, ...) where only the existence/identity of a change is needed to trigger a reconcile.
+	Add(trigger schema.GroupVersionKind, options metav1.ListOptions, target metav1.ObjectMeta, metadataOnly bool) error
+}
+
+// WatchOption configures the watches established by WatchAll.
+type WatchOption func(*watchAllOptions)
+
+type watchAllOptions struct {
+	metadataOnly bool
+}
+
+// WithMetadataOnlyWatch configures WatchAll to establish its watches using PartialObjectMetadata
+// rather than full objects. This is the WatchAll-level counterpart of the
+// WithMetadataOnlyWatches reconciler option: a Reconciler with that option set passes
+// WithMetadataOnlyWatch through to its call to WatchAll so the flag actually takes effect.
+func WithMetadataOnlyWatch() WatchOption {
+	return func(o *watchAllOptions) {
+		o.metadataOnly = true
+	}
 }
 
 // WatchAll creates a Watch on ctrl for all objects reconciled by recnl
-func WatchAll(config *rest.Config, ctrl controller.Controller, recnl Source) (chan struct{}, error) {
+func WatchAll(config *rest.Config, ctrl controller.Controller, recnl Source, opts ...WatchOption) (chan struct{}, error) {
+	var o watchAllOptions
+	for _, opt := range opts {
+		opt(&o)
+	}
+	return watchAllWithOptions(config, ctrl, recnl, o.metadataOnly)
+}
+
+func watchAllWithOptions(config *rest.Config, ctrl controller.Controller, recnl Source, metadataOnly bool) (chan struct{}, error) {
 	dw, events, err := watch.NewDynamicWatch(*config)
 	if err != nil {
 		return nil, fmt.Errorf("creating dynamic watch: %v", err)
@@ -40,13 +68,17 @@ func WatchAll(config *rest.Config, ctrl controller.Controller, recnl Source) (ch
 	if err := ctrl.Watch(src, &handler.EnqueueRequestForObject{}); err != nil {
 		return nil, fmt.Errorf("setting up dynamic watch on the controller: %v", err)
 	}
-	recnl.SetSink(&watchAll{dw, make(map[schema.GroupVersionKind]struct{})})
+	recnl.SetSink(&watchAll{dw, make(map[schema.GroupVersionKind]struct{}), metadataOnly})
 	return stopCh, nil
 }
 
 type watchAll struct {
 	dw         DynamicWatch
 	registered map[schema.GroupVersionKind]struct{}
+
+	// metadataOnly requests that watches be established using PartialObjectMetadata when the
+	// WithMetadataOnlyWatches reconciler option is set.
+	metadataOnly bool
 }
 
 func (w *watchAll) Notify(ctx context.Context, dest DeclarativeObject, objs *manifest.Objects) error {
@@ -68,7 +100,7 @@ func (w *watchAll) Notify(ctx context.Context, dest DeclarativeObject, objs *man
 			continue
 		}
 
-		err := w.dw.Add(gvk, filter, notify)
+		err := w.dw.Add(gvk, filter, notify, w.metadataOnly && metadataSufficesFor(gvk))
 		if err != nil {
 			log.WithValues("GroupVersionKind", gvk.String()).Error(err, "adding watch")
 			continue
@@ -79,6 +111,15 @@ func (w *watchAll) Notify(ctx context.Context, dest DeclarativeObject, objs *man
 	return nil
 }
 
+// metadataSufficesFor reports whether a watch on gvk only needs to observe object identity
+// (rather than spec/status) in order to trigger a reconcile. The reconciler only uses watch
+// events to decide *that* something changed, not *what* changed, so metadata is sufficient for
+// every kind today; this is split out as its own hook so a future per-GVK exclusion list (for
+// transforms that inspect the watched object's spec/status) has somewhere to live.
+func metadataSufficesFor(gvk schema.GroupVersionKind) bool {
+	return true
+}
+
 // uniqueGroupVersionKind returns all unique GroupVersionKind defined in objects
 func uniqueGroupVersionKind(objects *manifest.Objects) []schema.GroupVersionKind {
 	kinds := map[schema.GroupVersionKind]struct{}{}
@@ -0,0 +1,352 @@
+package declarative
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"sigs.k8s.io/kubebuilder-declarative-pattern/pkg/patterns/declarative/pkg/manifest"
+)
+
+// waveApplyFieldManager is the field manager used when OrderedApply applies a wave's objects.
+const waveApplyFieldManager = "kubebuilder-declarative-pattern"
+
+// wavePollInterval is how often ApplyOrdered polls a wave's objects for readiness.
+const wavePollInterval = 2 * time.Second
+
+// ApplyStrategy selects how a resolved manifest is applied to the cluster.
+type ApplyStrategy int
+
+const (
+	// DefaultApply applies the whole manifest in one shot, relying on `kubectl apply --prune`
+	// (see WithApplyPrune) for cleanup. This is the strategy used if WithApplyStrategy is never
+	// called.
+	DefaultApply ApplyStrategy = iota
+
+	// OrderedApply groups the manifest's objects into dependency-ordered waves - namespaces,
+	// then CRDs, then RBAC, then workloads, by default - applying one wave at a time and
+	// waiting for its objects to become ready before moving on to the next. This mirrors the
+	// ordering kapp performs to avoid races like a Deployment coming up before the CRD its
+	// operator watches has been established.
+	OrderedApply
+)
+
+// ApplyWaveAnnotation overrides the default kind-priority wave for the annotated object with an
+// explicit wave number. Lower numbers are applied first.
+const ApplyWaveAnnotation = "kdp.k8s.io/apply-wave"
+
+// DependsOnAnnotation names another object, as "<group>/<kind>/<name>", that must be applied and
+// ready before the annotated object is applied. Multiple dependencies are comma-separated.
+const DependsOnAnnotation = "kdp.k8s.io/depends-on"
+
+// WaveConditionType is the Status condition type surfaced when wave n fails to apply or become
+// ready under OrderedApply, e.g. "Wave2".
+func WaveConditionType(wave int) string {
+	return fmt.Sprintf("Wave%d", wave)
+}
+
+// WaveError is returned by ApplyOrdered when Wave fails to apply or become ready.
+type WaveError struct {
+	Wave int
+	Err  error
+}
+
+func (e *WaveError) Error() string { return e.Err.Error() }
+
+func (e *WaveError) Unwrap() error { return e.Err }
+
+// defaultKindPriority orders kinds that have no explicit apply-wave annotation and no
+// depends-on relationship to another object. Earlier entries are applied first; kinds not
+// listed share the wave right after all of these.
+var defaultKindPriority = []string{
+	"Namespace",
+	"CustomResourceDefinition",
+	"ServiceAccount",
+	"ClusterRole",
+	"ClusterRoleBinding",
+	"Role",
+	"RoleBinding",
+	"ConfigMap",
+	"Secret",
+	"Service",
+}
+
+// Wave is a group of manifest objects that should be applied together, and whose readiness is
+// awaited before the next wave is applied.
+type Wave struct {
+	Number  int
+	Objects []*manifest.Object
+}
+
+// ComputeWaves groups objs into ordered Waves. Each object starts in the wave given by
+// defaultKindPriority (kinds not listed there share the wave right after the listed ones), which
+// an explicit ApplyWaveAnnotation replaces; a topological pass over DependsOnAnnotation
+// relationships then moves an object to a later wave where needed, so a dependency is always
+// placed in an earlier wave than its dependents, even over an explicit annotation.
+func ComputeWaves(objs *manifest.Objects) ([]Wave, error) {
+	byKey := map[string]*manifest.Object{}
+	for _, o := range objs.Items {
+		byKey[dependencyKey(o)] = o
+	}
+
+	wave := map[*manifest.Object]int{}
+	visiting := map[*manifest.Object]bool{}
+
+	var assign func(o *manifest.Object) (int, error)
+	assign = func(o *manifest.Object) (int, error) {
+		if w, ok := wave[o]; ok {
+			return w, nil
+		}
+		if visiting[o] {
+			return 0, fmt.Errorf("cycle detected in %s annotations involving %s", DependsOnAnnotation, dependencyKey(o))
+		}
+		visiting[o] = true
+		defer delete(visiting, o)
+
+		w := kindPriorityWave(o)
+		if explicit, ok := explicitWave(o); ok {
+			w = explicit
+		}
+
+		for _, depKey := range dependsOn(o) {
+			dep, ok := byKey[depKey]
+			if !ok {
+				return 0, fmt.Errorf("object %s declares %s=%s but no such object is in the manifest", dependencyKey(o), DependsOnAnnotation, depKey)
+			}
+			depWave, err := assign(dep)
+			if err != nil {
+				return 0, err
+			}
+			if depWave >= w {
+				w = depWave + 1
+			}
+		}
+
+		wave[o] = w
+		return w, nil
+	}
+
+	byNumber := map[int][]*manifest.Object{}
+	for _, o := range objs.Items {
+		w, err := assign(o)
+		if err != nil {
+			return nil, err
+		}
+		byNumber[w] = append(byNumber[w], o)
+	}
+
+	var numbers []int
+	for n := range byNumber {
+		numbers = append(numbers, n)
+	}
+	sort.Ints(numbers)
+
+	var waves []Wave
+	for _, n := range numbers {
+		waves = append(waves, Wave{Number: n, Objects: byNumber[n]})
+	}
+	return waves, nil
+}
+
+func kindPriorityWave(o *manifest.Object) int {
+	for i, kind := range defaultKindPriority {
+		if o.Kind == kind {
+			return i
+		}
+	}
+	return len(defaultKindPriority)
+}
+
+func explicitWave(o *manifest.Object) (int, bool) {
+	raw, ok := o.UnstructuredObject().GetAnnotations()[ApplyWaveAnnotation]
+	if !ok {
+		return 0, false
+	}
+	var wave int
+	if _, err := fmt.Sscanf(raw, "%d", &wave); err != nil {
+		return 0, false
+	}
+	return wave, true
+}
+
+func dependsOn(o *manifest.Object) []string {
+	raw, ok := o.UnstructuredObject().GetAnnotations()[DependsOnAnnotation]
+	if !ok || raw == "" {
+		return nil
+	}
+	var deps []string
+	start := 0
+	for i := 0; i <= len(raw); i++ {
+		if i == len(raw) || raw[i] == ',' {
+			if i > start {
+				deps = append(deps, raw[start:i])
+			}
+			start = i + 1
+		}
+	}
+	return deps
+}
+
+func dependencyKey(o *manifest.Object) string {
+	return fmt.Sprintf("%s/%s/%s", o.Group, o.Kind, o.UnstructuredObject().GetName())
+}
+
+// ReadinessChecker reports whether a deployed object has become ready, so OrderedApply can decide
+// when to proceed to the next wave.
+type ReadinessChecker interface {
+	IsReady(ctx context.Context, u *unstructured.Unstructured) (bool, error)
+}
+
+// defaultReadinessChecker implements the readiness rules used by OrderedApply unless a caller
+// supplies its own ReadinessChecker: Deployment/StatefulSet are ready once
+// status.observedGeneration matches metadata.generation and availableReplicas meets the desired
+// replica count; DaemonSet is ready once status.observedGeneration matches and
+// numberAvailable meets desiredNumberScheduled; Job is ready once status.succeeded is at least 1;
+// CustomResourceDefinition is ready once its Established condition is True; everything else falls
+// back to a generic status.conditions[type=Ready].status == "True" check.
+type defaultReadinessChecker struct{}
+
+func (defaultReadinessChecker) IsReady(ctx context.Context, u *unstructured.Unstructured) (bool, error) {
+	switch u.GetKind() {
+	case "Deployment", "StatefulSet":
+		return workloadReady(u)
+	case "DaemonSet":
+		return daemonSetReady(u)
+	case "Job":
+		return jobReady(u)
+	case "CustomResourceDefinition":
+		return conditionTrue(u, "Established")
+	default:
+		return conditionTrue(u, "Ready")
+	}
+}
+
+func workloadReady(u *unstructured.Unstructured) (bool, error) {
+	generation := u.GetGeneration()
+	observedGeneration, _, err := unstructured.NestedInt64(u.Object, "status", "observedGeneration")
+	if err != nil {
+		return false, err
+	}
+	if observedGeneration != generation {
+		return false, nil
+	}
+
+	replicas, found, err := unstructured.NestedInt64(u.Object, "spec", "replicas")
+	if err != nil {
+		return false, err
+	}
+	if !found {
+		replicas = 1
+	}
+
+	available, _, err := unstructured.NestedInt64(u.Object, "status", "availableReplicas")
+	if err != nil {
+		return false, err
+	}
+	return available >= replicas, nil
+}
+
+func daemonSetReady(u *unstructured.Unstructured) (bool, error) {
+	generation := u.GetGeneration()
+	observedGeneration, _, err := unstructured.NestedInt64(u.Object, "status", "observedGeneration")
+	if err != nil {
+		return false, err
+	}
+	if observedGeneration != generation {
+		return false, nil
+	}
+
+	desired, _, err := unstructured.NestedInt64(u.Object, "status", "desiredNumberScheduled")
+	if err != nil {
+		return false, err
+	}
+	available, _, err := unstructured.NestedInt64(u.Object, "status", "numberAvailable")
+	if err != nil {
+		return false, err
+	}
+	return available >= desired, nil
+}
+
+func jobReady(u *unstructured.Unstructured) (bool, error) {
+	succeeded, _, err := unstructured.NestedInt64(u.Object, "status", "succeeded")
+	if err != nil {
+		return false, err
+	}
+	return succeeded >= 1, nil
+}
+
+func conditionTrue(u *unstructured.Unstructured, conditionType string) (bool, error) {
+	conditions, _, err := unstructured.NestedSlice(u.Object, "status", "conditions")
+	if err != nil {
+		return false, err
+	}
+	for _, c := range conditions {
+		condition, ok := c.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		if condition["type"] == conditionType {
+			return condition["status"] == "True", nil
+		}
+	}
+	return false, nil
+}
+
+// WaveTimeout is the default per-wave readiness timeout used by OrderedApply when a reconciler
+// option does not override it.
+const WaveTimeout = 5 * time.Minute
+
+// ApplyOrdered applies waves in order: every object in a wave is applied (via server-side apply,
+// taking ownership of any conflicting fields) before the wave's objects are polled for readiness,
+// and the next wave is only started once every object in the current one reports ready. If
+// checker is nil, defaultReadinessChecker is used; if timeout is zero, WaveTimeout is used.
+//
+// If a wave's objects do not become ready within timeout, ApplyOrdered stops and returns a
+// *WaveError identifying the wave that failed, so the caller can surface
+// WaveConditionType(err.Wave) as a Status condition.
+func ApplyOrdered(ctx context.Context, c client.Client, waves []Wave, checker ReadinessChecker, timeout time.Duration) error {
+	if checker == nil {
+		checker = defaultReadinessChecker{}
+	}
+	if timeout <= 0 {
+		timeout = WaveTimeout
+	}
+
+	for _, wave := range waves {
+		for _, o := range wave.Objects {
+			u := o.UnstructuredObject()
+			if err := c.Patch(ctx, u, client.Apply, client.FieldOwner(waveApplyFieldManager), client.ForceOwnership); err != nil {
+				return &WaveError{Wave: wave.Number, Err: fmt.Errorf("applying %s in wave %d: %v", dependencyKey(o), wave.Number, err)}
+			}
+		}
+
+		waveCtx, cancel := context.WithTimeout(ctx, timeout)
+		err := wait.PollImmediateUntil(wavePollInterval, func() (bool, error) {
+			for _, o := range wave.Objects {
+				live := o.UnstructuredObject().DeepCopy()
+				if err := c.Get(waveCtx, client.ObjectKeyFromObject(live), live); err != nil {
+					return false, err
+				}
+				ready, err := checker.IsReady(waveCtx, live)
+				if err != nil {
+					return false, err
+				}
+				if !ready {
+					return false, nil
+				}
+			}
+			return true, nil
+		}, waveCtx.Done())
+		cancel()
+		if err != nil {
+			return &WaveError{Wave: wave.Number, Err: fmt.Errorf("objects in wave %d did not become ready within %s: %v", wave.Number, timeout, err)}
+		}
+	}
+
+	return nil
+}
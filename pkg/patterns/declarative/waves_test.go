@@ -0,0 +1,146 @@
+package declarative
+
+import (
+	"context"
+	"testing"
+
+	"sigs.k8s.io/kubebuilder-declarative-pattern/pkg/patterns/declarative/pkg/manifest"
+)
+
+func mustParseWaveObjects(t *testing.T, yaml string) *manifest.Objects {
+	t.Helper()
+	objs, err := manifest.ParseObjects(context.Background(), yaml)
+	if err != nil {
+		t.Fatalf("parsing test manifest: %v", err)
+	}
+	return objs
+}
+
+func TestComputeWavesDefaultKindPriority(t *testing.T) {
+	objs := mustParseWaveObjects(t, `
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: my-deployment
+---
+apiVersion: v1
+kind: Namespace
+metadata:
+  name: my-namespace
+---
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: my-configmap
+`)
+
+	waves, err := ComputeWaves(objs)
+	if err != nil {
+		t.Fatalf("ComputeWaves: %v", err)
+	}
+
+	waveForKind := map[string]int{}
+	for _, w := range waves {
+		for _, o := range w.Objects {
+			waveForKind[o.Kind] = w.Number
+		}
+	}
+
+	if waveForKind["Namespace"] >= waveForKind["ConfigMap"] {
+		t.Errorf("expected Namespace to be applied before ConfigMap, got waves %v", waveForKind)
+	}
+	if waveForKind["ConfigMap"] >= waveForKind["Deployment"] {
+		t.Errorf("expected ConfigMap to be applied before Deployment (which falls back to the final wave), got waves %v", waveForKind)
+	}
+}
+
+func TestComputeWavesExplicitAnnotationOverridesKindPriority(t *testing.T) {
+	objs := mustParseWaveObjects(t, `
+apiVersion: v1
+kind: Namespace
+metadata:
+  name: my-namespace
+  annotations:
+    kdp.k8s.io/apply-wave: "5"
+---
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: my-deployment
+  annotations:
+    kdp.k8s.io/apply-wave: "0"
+`)
+
+	waves, err := ComputeWaves(objs)
+	if err != nil {
+		t.Fatalf("ComputeWaves: %v", err)
+	}
+
+	waveForKind := map[string]int{}
+	for _, w := range waves {
+		for _, o := range w.Objects {
+			waveForKind[o.Kind] = w.Number
+		}
+	}
+
+	if waveForKind["Deployment"] != 0 {
+		t.Errorf("expected Deployment in wave 0, got %d", waveForKind["Deployment"])
+	}
+	if waveForKind["Namespace"] != 5 {
+		t.Errorf("expected Namespace in wave 5, got %d", waveForKind["Namespace"])
+	}
+}
+
+func TestComputeWavesDependsOnOrdersAfterDependency(t *testing.T) {
+	objs := mustParseWaveObjects(t, `
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: my-deployment
+  annotations:
+    kdp.k8s.io/depends-on: "apps/Deployment/my-other-deployment"
+---
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: my-other-deployment
+`)
+
+	waves, err := ComputeWaves(objs)
+	if err != nil {
+		t.Fatalf("ComputeWaves: %v", err)
+	}
+
+	waveForName := map[string]int{}
+	for _, w := range waves {
+		for _, o := range w.Objects {
+			waveForName[o.UnstructuredObject().GetName()] = w.Number
+		}
+	}
+
+	if waveForName["my-other-deployment"] >= waveForName["my-deployment"] {
+		t.Errorf("expected my-other-deployment to be applied before its dependent my-deployment, got waves %v", waveForName)
+	}
+}
+
+func TestComputeWavesDetectsCycle(t *testing.T) {
+	objs := mustParseWaveObjects(t, `
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: a
+  annotations:
+    kdp.k8s.io/depends-on: "apps/Deployment/b"
+---
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: b
+  annotations:
+    kdp.k8s.io/depends-on: "apps/Deployment/a"
+`)
+
+	if _, err := ComputeWaves(objs); err == nil {
+		t.Fatal("expected an error for a cyclic depends-on relationship, got nil")
+	}
+}